@@ -0,0 +1,50 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Server holds the dependencies the HTTP handlers need, so tests and
+// alternate ErrorStore implementations can be injected instead of relying
+// on package-level state.
+type Server struct {
+	Store ErrorStore
+}
+
+func NewServer(store ErrorStore) *Server {
+	return &Server{Store: store}
+}
+
+func (s *Server) evaluateHandler(request *http.Request) (int, any, error) {
+	var problemExpr ProblemExpression
+	if err := BindAndValidate(request, &problemExpr); err != nil {
+		return 0, nil, err
+	}
+
+	result, message := evaluateExpression(problemExpr.Expression)
+	if message != "" {
+		s.Store.Record(EvaluateEndpoint, problemExpr.Expression, message)
+		return http.StatusOK, EvaluationResponse{Result: message}, nil
+	}
+
+	return http.StatusOK, EvaluationResponse{Result: strconv.FormatFloat(result, 'f', -1, 64)}, nil
+}
+
+func (s *Server) validateHandler(request *http.Request) (int, any, error) {
+	var problemExpr ProblemExpression
+	if err := BindAndValidate(request, &problemExpr); err != nil {
+		return 0, nil, err
+	}
+
+	valid, reason := validateExpression(problemExpr.Expression)
+	if reason != "" {
+		s.Store.Record(ValidateEndpoint, problemExpr.Expression, reason)
+	}
+
+	return http.StatusOK, ValidationResponse{Valid: valid, Reason: reason}, nil
+}
+
+func (s *Server) errorsHandler(request *http.Request) (int, any, error) {
+	return http.StatusOK, s.Store.Snapshot(), nil
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// validate is shared across requests; a *validator.Validate caches struct
+// metadata internally and is safe for concurrent use once built.
+var validate = validator.New()
+
+// BindAndValidate decodes the request body into dst and validates it against
+// its `validate` struct tags, returning a typed 400 *HTTPError with
+// field-level messages if either step fails.
+func BindAndValidate[T any](r *http.Request, dst *T) error {
+	if err := decodeJSON(r, dst); err != nil {
+		return err
+	}
+
+	if err := validate.Struct(dst); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if errors.As(err, &fieldErrs) {
+			return NewHTTPError(http.StatusBadRequest, validationMessage(fieldErrs), err)
+		}
+		return NewHTTPError(http.StatusBadRequest, InvalidJSONError, err)
+	}
+
+	return nil
+}
+
+// validationMessage turns validator.ValidationErrors into a single
+// human-readable summary, one clause per offending field.
+func validationMessage(fieldErrs validator.ValidationErrors) string {
+	clauses := make([]string, 0, len(fieldErrs))
+	for _, fe := range fieldErrs {
+		clauses = append(clauses, fmt.Sprintf("%s failed %s", strings.ToLower(fe.Field()), fe.Tag()))
+	}
+
+	return strings.Join(clauses, "; ")
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// unaryMinusOp is the internal operator token for a leading "minus" used as
+// a negation prefix (e.g. "minus 5"), distinct from the binary subtraction
+// KeywordMinus.
+const unaryMinusOp = "unary_minus"
+
+type tokenKind int
+
+const (
+	tokenNumber tokenKind = iota
+	tokenOperator
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind tokenKind
+	num  float64
+	op   string
+}
+
+// precedence and rightAssoc describe the shunting-yard operator table.
+// Multiplied/divided/modulo bind tighter than plus/minus; raised-to-the-
+// power-of binds tightest and is right-associative, matching the usual
+// mathematical convention (2 raised to the power of 3 raised to the power
+// of 2 = 2^(3^2)). Unary minus binds tightest of all.
+var precedence = map[string]int{
+	KeywordPlus:       1,
+	KeywordMinus:      1,
+	KeywordMultiplied: 2,
+	KeywordDivided:    2,
+	KeywordModulo:     2,
+	KeywordRaised:     3,
+	unaryMinusOp:      4,
+}
+
+var rightAssoc = map[string]bool{
+	KeywordRaised: true,
+	unaryMinusOp:  true,
+}
+
+// tokenizeExpression turns a "What is ...?" question into a token stream,
+// validating keyword phrases (divided by, raised to the power of) and
+// operand/operator placement along the way.
+func tokenizeExpression(expression string) ([]token, string) {
+	if len(expression) == 0 || !strings.HasSuffix(expression, "?") {
+		return nil, InvalidExpressionError
+	}
+
+	body := strings.ToLower(expression[:len(expression)-1])
+	body = strings.ReplaceAll(body, "(", " ( ")
+	body = strings.ReplaceAll(body, ")", " ) ")
+	fields := strings.Fields(body)
+
+	if len(fields) < 3 || fields[0] != KeywordWhat || fields[1] != KeywordIs {
+		return nil, NonMathQuestionError
+	}
+	fields = fields[2:]
+
+	var tokens []token
+	expectOperand := true
+	depth := 0
+
+	for i := 0; i < len(fields); i++ {
+		word := fields[i]
+
+		if expectOperand {
+			switch word {
+			case "(":
+				depth++
+				tokens = append(tokens, token{kind: tokenLParen})
+			case KeywordMinus:
+				tokens = append(tokens, token{kind: tokenOperator, op: unaryMinusOp})
+			default:
+				num, err := strconv.ParseFloat(word, 64)
+				if err != nil {
+					return nil, InvalidExpressionError
+				}
+				tokens = append(tokens, token{kind: tokenNumber, num: num})
+				expectOperand = false
+			}
+			continue
+		}
+
+		switch word {
+		case ")":
+			if depth == 0 {
+				return nil, InvalidExpressionError
+			}
+			depth--
+			tokens = append(tokens, token{kind: tokenRParen})
+		case KeywordPlus, KeywordMinus, KeywordModulo:
+			tokens = append(tokens, token{kind: tokenOperator, op: word})
+			expectOperand = true
+		case KeywordMultiplied, KeywordDivided:
+			if i+1 >= len(fields) || fields[i+1] != KeywordBy {
+				return nil, InvalidExpressionError
+			}
+			i++
+			tokens = append(tokens, token{kind: tokenOperator, op: word})
+			expectOperand = true
+		case KeywordRaised:
+			if i+4 >= len(fields) || fields[i+1] != KeywordTo || fields[i+2] != KeywordThe ||
+				fields[i+3] != KeywordPower || fields[i+4] != KeywordOf {
+				return nil, InvalidExpressionError
+			}
+			i += 4
+			tokens = append(tokens, token{kind: tokenOperator, op: KeywordRaised})
+			expectOperand = true
+		default:
+			return nil, UnsupportedOperationError
+		}
+	}
+
+	if expectOperand || depth != 0 {
+		return nil, InvalidExpressionError
+	}
+
+	return tokens, ""
+}
+
+// toRPN runs the shunting-yard algorithm over tokens, producing them in
+// reverse-Polish order ready for a straight left-to-right evaluation.
+func toRPN(tokens []token) ([]token, string) {
+	output := make([]token, 0, len(tokens))
+	var opStack []token
+
+	for _, tok := range tokens {
+		switch tok.kind {
+		case tokenNumber:
+			output = append(output, tok)
+		case tokenLParen:
+			opStack = append(opStack, tok)
+		case tokenRParen:
+			for len(opStack) > 0 && opStack[len(opStack)-1].kind != tokenLParen {
+				output = append(output, opStack[len(opStack)-1])
+				opStack = opStack[:len(opStack)-1]
+			}
+			if len(opStack) == 0 {
+				return nil, InvalidExpressionError
+			}
+			opStack = opStack[:len(opStack)-1]
+		case tokenOperator:
+			for len(opStack) > 0 {
+				top := opStack[len(opStack)-1]
+				if top.kind != tokenOperator {
+					break
+				}
+				if precedence[top.op] > precedence[tok.op] ||
+					(precedence[top.op] == precedence[tok.op] && !rightAssoc[tok.op]) {
+					output = append(output, top)
+					opStack = opStack[:len(opStack)-1]
+					continue
+				}
+				break
+			}
+			opStack = append(opStack, tok)
+		}
+	}
+
+	for len(opStack) > 0 {
+		top := opStack[len(opStack)-1]
+		if top.kind == tokenLParen {
+			return nil, InvalidExpressionError
+		}
+		output = append(output, top)
+		opStack = opStack[:len(opStack)-1]
+	}
+
+	return output, ""
+}
+
+func evalRPN(rpn []token) (float64, string) {
+	stack := make([]float64, 0, len(rpn))
+
+	for _, tok := range rpn {
+		if tok.kind == tokenNumber {
+			stack = append(stack, tok.num)
+			continue
+		}
+
+		if tok.op == unaryMinusOp {
+			if len(stack) < 1 {
+				return 0, InvalidExpressionError
+			}
+			stack[len(stack)-1] = -stack[len(stack)-1]
+			continue
+		}
+
+		if len(stack) < 2 {
+			return 0, InvalidExpressionError
+		}
+
+		b := stack[len(stack)-1]
+		a := stack[len(stack)-2]
+		stack = stack[:len(stack)-2]
+
+		var result float64
+		switch tok.op {
+		case KeywordPlus:
+			result = a + b
+		case KeywordMinus:
+			result = a - b
+		case KeywordMultiplied:
+			result = a * b
+		case KeywordDivided:
+			if b == 0 {
+				return 0, InvalidExpressionError
+			}
+			result = a / b
+		case KeywordModulo:
+			if b == 0 {
+				return 0, InvalidExpressionError
+			}
+			result = math.Mod(a, b)
+		case KeywordRaised:
+			result = math.Pow(a, b)
+		default:
+			return 0, UnsupportedOperationError
+		}
+
+		stack = append(stack, result)
+	}
+
+	if len(stack) != 1 {
+		return 0, InvalidExpressionError
+	}
+
+	return stack[0], ""
+}
+
+func validateExpression(expression string) (bool, string) {
+	tokens, reason := tokenizeExpression(expression)
+	if reason != "" {
+		return false, reason
+	}
+
+	rpn, reason := toRPN(tokens)
+	if reason != "" {
+		return false, reason
+	}
+
+	if _, reason := evalRPN(rpn); reason != "" {
+		return false, reason
+	}
+
+	return true, ""
+}
+
+func evaluateExpression(expression string) (float64, string) {
+	tokens, reason := tokenizeExpression(expression)
+	if reason != "" {
+		return 0, reason
+	}
+
+	rpn, reason := toRPN(tokens)
+	if reason != "" {
+		return 0, reason
+	}
+
+	return evalRPN(rpn)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateExpressionPrecedenceAndParens(t *testing.T) {
+	cases := []struct {
+		name       string
+		expression string
+		expected   float64
+	}{
+		{"multiplication before addition", "What is 2 plus 3 multiplied by 4?", 14},
+		{"parens override precedence", "What is (2 plus 3) multiplied by 4?", 20},
+		{"decimals", "What is 0.5 plus 0.25?", 0.75},
+		{"unary minus", "What is minus 5 plus 10?", 5},
+		{"modulo", "What is 10 modulo 3?", 1},
+		{"raised to the power of", "What is 2 raised to the power of 3?", 8},
+		{"right-associative power", "What is 2 raised to the power of 3 raised to the power of 2?", 512},
+		{"nested parens", "What is (2 plus (3 multiplied by 4)) minus 1?", 13},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, reason := evaluateExpression(tc.expression)
+			assert.Equal(t, reason, "", "%s: expected no error, but got %s", tc.name, reason)
+			assert.Equal(t, result, tc.expected, "%s: expected %f, but got %f", tc.name, tc.expected, result)
+		})
+	}
+}
+
+func TestEvaluateExpressionZeroDivisor(t *testing.T) {
+	cases := []struct {
+		name       string
+		expression string
+	}{
+		{"modulo by zero", "What is 10 modulo 0?"},
+		{"parenthesized zero divisor", "What is 10 divided by (5 minus 5)?"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, reason := evaluateExpression(tc.expression)
+			assert.Equal(t, reason, InvalidExpressionError, "%s: expected %s, but got %s", tc.name, InvalidExpressionError, reason)
+		})
+	}
+}
+
+func TestValidateExpressionRichGrammar(t *testing.T) {
+	cases := []struct {
+		name       string
+		expression string
+		valid      bool
+	}{
+		{"mismatched parens", "What is (2 plus 3 multiplied by 4?", false},
+		{"empty parens", "What is ( ) plus 4?", false},
+		{"valid nested parens", "What is (2 plus 3) multiplied by (4 minus 1)?", true},
+		{"unary minus then operator", "What is minus 5 multiplied by minus 2?", true},
+		{"modulo by zero", "What is 10 modulo 0?", false},
+		{"parenthesized zero divisor", "What is 10 divided by (5 minus 5)?", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			valid, reason := validateExpression(tc.expression)
+			assert.Equal(t, valid, tc.valid, "%s: expected valid=%v, but got %v (reason %q)", tc.name, tc.valid, valid, reason)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// MaxRequestBodyBytes caps the size of a request body the JSON handlers
+// will decode, so an oversize payload fails fast instead of being read in
+// full.
+const MaxRequestBodyBytes = 1 << 20 // 1 MiB
+
+// HTTPError is a typed error carrying the status code it should produce,
+// plus an optional underlying cause for logging.
+type HTTPError struct {
+	Code    int
+	Message string
+	Cause   error
+}
+
+func NewHTTPError(code int, message string, cause error) *HTTPError {
+	return &HTTPError{Code: code, Message: message, Cause: cause}
+}
+
+// Error satisfies the error interface with the stable, client-facing
+// message; use LogString for a variant that includes Cause.
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+// LogString includes Cause, for server-side logging where the underlying
+// detail is useful but should not leak into the client-facing envelope.
+func (e *HTTPError) LogString() string {
+	if e.Cause != nil {
+		return fmt.Sprintf("%s: %v", e.Message, e.Cause)
+	}
+	return e.Message
+}
+
+func (e *HTTPError) HTTPCode() int {
+	return e.Code
+}
+
+// JSONHandlerFunc is a handler that returns its result instead of writing
+// it directly, so the envelope and status code are applied uniformly.
+type JSONHandlerFunc func(*http.Request) (status int, data any, err error)
+
+type jsonEnvelope struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   any    `json:"data,omitempty"`
+}
+
+func (f JSONHandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes)
+
+	status, data, err := f(r)
+
+	w.Header().Set("Content-Type", "application/json")
+
+	if err != nil {
+		code := http.StatusInternalServerError
+		var coder interface{ HTTPCode() int }
+		if errors.As(err, &coder) {
+			code = coder.HTTPCode()
+		}
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) {
+			fmt.Println(httpErr.LogString())
+		}
+
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(jsonEnvelope{Status: "error", Error: err.Error()})
+		return
+	}
+
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(jsonEnvelope{Status: "success", Data: data})
+}
+
+// decodeEnvelope unwraps a jsonEnvelope response body into dst, for callers
+// such as the REPL that consume the handlers' output directly rather than
+// over the wire.
+func decodeEnvelope(r io.Reader, dst any) error {
+	var env struct {
+		Status string          `json:"status"`
+		Error  string          `json:"error,omitempty"`
+		Data   json.RawMessage `json:"data,omitempty"`
+	}
+
+	if err := json.NewDecoder(r).Decode(&env); err != nil {
+		return err
+	}
+
+	if env.Status == "error" {
+		return errors.New(env.Error)
+	}
+
+	if len(env.Data) == 0 {
+		return nil
+	}
+
+	return json.Unmarshal(env.Data, dst)
+}
+
+// decodeJSON decodes the request body into dst, returning a typed
+// *HTTPError that distinguishes an oversize body from other malformed or
+// empty input.
+func decodeJSON(r *http.Request, dst any) error {
+	defer r.Body.Close()
+
+	dec := json.NewDecoder(r.Body)
+	if err := dec.Decode(dst); err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return NewHTTPError(http.StatusRequestEntityTooLarge, "request body too large", err)
+		}
+
+		return NewHTTPError(http.StatusBadRequest, InvalidJSONError, err)
+	}
+
+	if dec.More() {
+		return NewHTTPError(http.StatusBadRequest, InvalidJSONError, errors.New("unexpected trailing data"))
+	}
+
+	return nil
+}
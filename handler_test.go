@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONHandlerFuncErrorEnvelope(t *testing.T) {
+	cases := []struct {
+		name        string
+		body        string
+		contentType string
+	}{
+		{"malformed JSON", `{"expression": "What is 2 plus 3?"`, "application/json"},
+		{"empty body", "", "application/json"},
+		{"non-JSON content type", "not even json", "text/plain"},
+	}
+
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler), POST_REQ)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+
+			assert.Equal(t, res.Code, http.StatusBadRequest, "%s: expected Status 400, but got %d", tc.name, res.Code)
+			assert.Equal(t, res.Header().Get("Content-Type"), "application/json", "%s: expected a JSON response", tc.name)
+
+			var envelope struct {
+				Status string `json:"status"`
+				Error  string `json:"error"`
+			}
+			err := json.NewDecoder(res.Body).Decode(&envelope)
+			assert.Nil(t, err, "%s: expected the error envelope itself to be valid JSON, but got %v", tc.name, err)
+			assert.Equal(t, envelope.Status, "error", "%s: expected status \"error\", but got %s", tc.name, envelope.Status)
+			assert.Equal(t, envelope.Error, InvalidJSONError, "%s: expected message %s, but got %s", tc.name, InvalidJSONError, envelope.Error)
+		})
+	}
+}
+
+func TestJSONHandlerFuncOversizeBody(t *testing.T) {
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler), POST_REQ)
+
+	oversize := strings.Repeat("a", MaxRequestBodyBytes+1)
+	body := `{"expression": "` + oversize + `"}`
+
+	req := httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(body))
+	res := httptest.NewRecorder()
+	router.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Code, http.StatusRequestEntityTooLarge, "Expected Status 413, but got %d", res.Code)
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+)
+
+// Options configures the middleware stack built by Middleware.
+type Options struct {
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// Option mutates an Options value; used as functional options to Middleware.
+type Option func(*Options)
+
+func WithAllowedOrigins(origins ...string) Option {
+	return func(o *Options) { o.AllowedOrigins = origins }
+}
+
+func WithAllowedMethods(methods ...string) Option {
+	return func(o *Options) { o.AllowedMethods = methods }
+}
+
+func WithAllowedHeaders(headers ...string) Option {
+	return func(o *Options) { o.AllowedHeaders = headers }
+}
+
+func defaultOptions() *Options {
+	return &Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{GET_REQ, POST_REQ},
+		AllowedHeaders: []string{"Content-Type"},
+	}
+}
+
+// Middleware wraps h with panic recovery, access logging, CORS, and gzip
+// compression, so tests can opt in/out per test by composing only the
+// pieces they need.
+func Middleware(h http.Handler, opts ...Option) http.Handler {
+	o := defaultOptions()
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return loggingMiddleware(corsMiddleware(gzipMiddleware(recoverMiddleware(h)), o))
+}
+
+func isAllowedOrigin(origin string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == "*" || a == origin {
+			return true
+		}
+	}
+
+	return false
+}
+
+func corsMiddleware(next http.Handler, o *Options) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if origin != "" && isAllowedOrigin(origin, o.AllowedOrigins) {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			w.Header().Set("Access-Control-Allow-Methods", strings.Join(o.AllowedMethods, ", "))
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(o.AllowedHeaders, ", "))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	gz *gzip.Writer
+}
+
+func (w *gzipResponseWriter) Write(b []byte) (int, error) {
+	return w.gz.Write(b)
+}
+
+func gzipMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Encoding", "gzip")
+
+		gz := gzip.NewWriter(w)
+		defer gz.Close()
+
+		next.ServeHTTP(&gzipResponseWriter{ResponseWriter: w, gz: gz}, r)
+	})
+}
+
+// statusRecorder tracks the status code and byte count a handler wrote, for
+// the access log.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+type accessLogEntry struct {
+	Method    string  `json:"method"`
+	Path      string  `json:"path"`
+	Status    int     `json:"status"`
+	Bytes     int     `json:"bytes"`
+	LatencyMs float64 `json:"latencyMs"`
+}
+
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+
+		next.ServeHTTP(rec, r)
+
+		line, _ := json.Marshal(accessLogEntry{
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			Status:    rec.status,
+			Bytes:     rec.bytes,
+			LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+		})
+
+		fmt.Println(string(line))
+	})
+}
+
+func recoverMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if rec := recover(); rec != nil {
+				fmt.Printf("panic: %v\n%s\n", rec, debug.Stack())
+
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				json.NewEncoder(w).Encode(jsonEnvelope{Status: "error", Error: "internal server error"})
+			}
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
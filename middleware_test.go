@@ -0,0 +1,48 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoverMiddlewarePanic(t *testing.T) {
+	panicking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(GET_REQ, "/", nil)
+	res := httptest.NewRecorder()
+
+	Middleware(panicking).ServeHTTP(res, req)
+
+	assert.Equal(t, res.Code, http.StatusInternalServerError, "Expected Status 500, but got %d", res.Code)
+	assert.Equal(t, res.Header().Get("Content-Type"), "application/json", "Expected a JSON response")
+
+	var envelope struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	err := json.NewDecoder(res.Body).Decode(&envelope)
+	assert.Nil(t, err, "Expected the error envelope itself to be valid JSON, but got %v", err)
+	assert.Equal(t, envelope.Status, "error", "Expected status \"error\", but got %s", envelope.Status)
+}
+
+func TestCORSMiddlewareRejectsDisallowedOrigin(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mw := Middleware(ok, WithAllowedOrigins("https://allowed.example.com"))
+
+	req := httptest.NewRequest(GET_REQ, "/", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	res := httptest.NewRecorder()
+
+	mw.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Header().Get("Access-Control-Allow-Origin"), "", "Expected no CORS header for a disallowed origin")
+}
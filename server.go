@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+const ShutdownTimeout = 5 * time.Second
+const RequestTimeout = 10 * time.Second
+
+// stopper lets multiple goroutines observe a single shutdown signal without
+// racing on who closes the channel.
+type stopper struct {
+	ch   chan struct{}
+	once sync.Once
+}
+
+func newStopper() *stopper {
+	return &stopper{ch: make(chan struct{})}
+}
+
+func (s *stopper) Stop() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+func (s *stopper) Done() <-chan struct{} {
+	return s.ch
+}
+
+// Run starts the HTTP server and the interactive REPL, and blocks until ctx
+// is done, a SIGINT/SIGTERM is received, or the server fails. On shutdown it
+// drains in-flight requests within ShutdownTimeout before returning.
+func Run(ctx context.Context) error {
+	store, err := NewFileErrorStore(DefaultErrorStorePath)
+	if err != nil {
+		return err
+	}
+
+	server := NewServer(store)
+
+	router := mux.NewRouter()
+	router.Handle(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler))
+	router.Handle(ValidateEndpoint, JSONHandlerFunc(server.validateHandler))
+	router.Handle(ErrorsEndpoint, JSONHandlerFunc(server.errorsHandler))
+
+	handler := Middleware(http.TimeoutHandler(router, RequestTimeout, "request timed out"))
+
+	srv := &http.Server{
+		Addr:    Port,
+		Handler: handler,
+	}
+
+	stop := newStopper()
+	go runREPL(stop, server)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		stop.Stop()
+		store.Flush()
+		return err
+	case <-sigCh:
+	case <-ctx.Done():
+	}
+
+	stop.Stop()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+
+	shutdownErr := srv.Shutdown(shutdownCtx)
+	if flushErr := store.Flush(); flushErr != nil && shutdownErr == nil {
+		shutdownErr = flushErr
+	}
+
+	return shutdownErr
+}
+
+// runREPL drives the interactive stdin prompt until stop fires or stdin is
+// closed, so it never outlives a graceful shutdown.
+func runREPL(stop *stopper, server *Server) {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(os.Stdin)
+		for {
+			fmt.Print("Enter <endpoint> <file, when-needed>, \"exit\" to leave: ")
+			if !scanner.Scan() {
+				return
+			}
+
+			select {
+			case lines <- scanner.Text():
+			case <-stop.Done():
+				return
+			}
+		}
+	}()
+
+	for {
+		var userInput string
+		select {
+		case <-stop.Done():
+			return
+		case text, ok := <-lines:
+			if !ok {
+				return
+			}
+			userInput = text
+		}
+
+		if userInput == "" {
+			fmt.Println("Try again..")
+			continue
+		}
+
+		commands := strings.Split(userInput, " ")
+		if commands[0] == "exit" {
+			return
+		}
+
+		message := ""
+		if len(commands) == 1 {
+			getReq, _ := http.NewRequest(GET_REQ, ErrorsEndpoint, nil)
+			getRes := httptest.NewRecorder()
+
+			Router(ErrorsEndpoint, JSONHandlerFunc(server.errorsHandler), GET_REQ).ServeHTTP(getRes, getReq)
+			var errorsInfo []ErrorInfo
+			decodeEnvelope(getRes.Body, &errorsInfo)
+
+			message = beautifyJson(errorsInfo)
+		} else if len(commands) == 2 {
+			jsonFile, err := os.Open(commands[1])
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+
+			byteVal, _ := readAndClose(jsonFile)
+
+			endpoint := EvaluateEndpoint
+			handler := JSONHandlerFunc(server.evaluateHandler)
+
+			if commands[0] == ValidateEndpoint {
+				endpoint = ValidateEndpoint
+				handler = JSONHandlerFunc(server.validateHandler)
+			}
+
+			innerRouter := Router(endpoint, handler, POST_REQ)
+			postReq := httptest.NewRequest(POST_REQ, endpoint, strings.NewReader(string(byteVal)))
+			postRes := httptest.NewRecorder()
+			innerRouter.ServeHTTP(postRes, postReq)
+
+			if endpoint == EvaluateEndpoint {
+				var errorResp EvaluationResponse
+				decodeEnvelope(postRes.Body, &errorResp)
+				message = beautifyJson(errorResp)
+			} else {
+				var errorResp ValidationResponse
+				decodeEnvelope(postRes.Body, &errorResp)
+				message = beautifyJson(errorResp)
+			}
+		} else {
+			message = "Unnsuported command list. Try <program_name> <endpoint> <file - optional>"
+		}
+
+		fmt.Println(message)
+	}
+}
+
+func readAndClose(f *os.File) ([]byte, error) {
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+func main() {
+	if err := Run(context.Background()); err != nil && err != http.ErrServerClosed {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
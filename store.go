@@ -0,0 +1,110 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// DefaultErrorStorePath is where Run persists error frequencies between
+// restarts.
+const DefaultErrorStorePath = "errors.json"
+
+// ErrorStore tracks how often each expression has failed evaluation or
+// validation, keyed by endpoint, so /errors can report frequencies without
+// handlers touching shared state directly.
+type ErrorStore interface {
+	Record(endpoint, expression, errorType string)
+	Snapshot() []ErrorInfo
+	Reset()
+}
+
+// MemoryErrorStore is an ErrorStore guarded by a mutex, safe for concurrent
+// use by handler goroutines.
+type MemoryErrorStore struct {
+	mu     sync.RWMutex
+	errors map[MessageType]MessageInfo
+}
+
+func NewMemoryErrorStore() *MemoryErrorStore {
+	return &MemoryErrorStore{errors: make(map[MessageType]MessageInfo)}
+}
+
+func (s *MemoryErrorStore) Record(endpoint, expression, errorType string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := MessageType{Content: expression, Endpoint: endpoint}
+	info, exists := s.errors[key]
+	if !exists {
+		info = MessageInfo{ErrorType: errorType}
+	}
+	info.Frequency += 1
+	s.errors[key] = info
+}
+
+func (s *MemoryErrorStore) Snapshot() []ErrorInfo {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := make([]ErrorInfo, 0, len(s.errors))
+	for message, info := range s.errors {
+		snapshot = append(snapshot, ErrorInfo{
+			Expression: message.Content,
+			Endpoint:   message.Endpoint,
+			Frequency:  info.Frequency,
+			Type:       info.ErrorType,
+		})
+	}
+
+	return snapshot
+}
+
+func (s *MemoryErrorStore) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errors = make(map[MessageType]MessageInfo)
+}
+
+// FileErrorStore is a MemoryErrorStore that loads its frequencies from a
+// JSON file at startup and can flush them back so they survive restarts.
+type FileErrorStore struct {
+	*MemoryErrorStore
+	path string
+}
+
+// NewFileErrorStore loads path if it exists, or starts empty if it doesn't.
+func NewFileErrorStore(path string) (*FileErrorStore, error) {
+	store := &FileErrorStore{MemoryErrorStore: NewMemoryErrorStore(), path: path}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return store, nil
+		}
+		return nil, err
+	}
+
+	var entries []ErrorInfo
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		key := MessageType{Content: entry.Expression, Endpoint: entry.Endpoint}
+		store.errors[key] = MessageInfo{Frequency: entry.Frequency, ErrorType: entry.Type}
+	}
+
+	return store, nil
+}
+
+// Flush persists the current snapshot to path as indented JSON.
+func (s *FileErrorStore) Flush() error {
+	data, err := json.MarshalIndent(s.Snapshot(), "", "    ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, data, 0644)
+}
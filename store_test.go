@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryErrorStoreConcurrentRecord(t *testing.T) {
+	const requests = 300
+	badJSON := `{"expression": "What is 100 minus?"}`
+
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler), POST_REQ)
+
+	var wg sync.WaitGroup
+	wg.Add(requests)
+	for i := 0; i < requests; i++ {
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(badJSON))
+			res := httptest.NewRecorder()
+			router.ServeHTTP(res, req)
+		}()
+	}
+	wg.Wait()
+
+	snapshot := server.Store.Snapshot()
+	assert.Len(t, snapshot, 1, "Expected a single distinct failing expression to be tracked")
+	assert.Equal(t, snapshot[0].Frequency, requests,
+		"Expected frequency %d, but got %d", requests, snapshot[0].Frequency)
+}
+
+func TestFileErrorStoreFlushAndReload(t *testing.T) {
+	path := t.TempDir() + "/errors.json"
+
+	store, err := NewFileErrorStore(path)
+	assert.Nil(t, err, "Expected to create a fresh store, but got", err)
+
+	store.Record(EvaluateEndpoint, "What is 2 plus?", InvalidExpressionError)
+	assert.Nil(t, store.Flush(), "Expected to flush without error")
+
+	reloaded, err := NewFileErrorStore(path)
+	assert.Nil(t, err, "Expected to reload the persisted store, but got", err)
+
+	snapshot := reloaded.Snapshot()
+	assert.Len(t, snapshot, 1, "Expected the persisted frequency to survive a reload")
+	assert.Equal(t, snapshot[0].Frequency, 1, "Expected frequency 1, but got %d", snapshot[0].Frequency)
+}
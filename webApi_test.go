@@ -1,7 +1,9 @@
 package main
 
 import (
+	"compress/gzip"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -10,27 +12,6 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestIsValidKeyword(t *testing.T) {
-	validKeywords := []string{"plus", "minus", "multiplied", "divided"}
-	invalidKeywords := []string{"keyword", "is", "by", "What"}
-
-	for _, kw := range validKeywords {
-		assert.True(t, isValidKeyword(kw), "%s should be a valid keyword", kw)
-	}
-
-	for _, kw := range invalidKeywords {
-		assert.False(t, isValidKeyword(kw), "%s should not be a valid keyword", kw)
-	}
-}
-
-func TestParseNum(t *testing.T) {
-	s := "42"
-	var expected float64 = 42
-
-	result := parseNum(s)
-	assert.Equal(t, result, expected, "Expected %f, but got %f", expected, result)
-}
-
 func TestValidateExpression(t *testing.T) {
 	expectedAReason := "Expected a reason, but got an empty string"
 
@@ -96,14 +77,16 @@ func TestEvaluateExpression(t *testing.T) {
 func TestEvaluateHandler(t *testing.T) {
 	validJSON := `{"expression": "What is 2 plus 3?"}`
 	invalidJSON := `{"exp": "What is 2 plus 3?"}`
-	router := Router(EvaluateEndpoint, evaluateHandler, POST_REQ)
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler), POST_REQ)
+	mw := Middleware(router)
 
 	req := httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(validJSON))
 	res := httptest.NewRecorder()
 	router.ServeHTTP(res, req)
 
 	var evalResponse EvaluationResponse
-	err := json.NewDecoder(res.Body).Decode(&evalResponse)
+	err := decodeEnvelope(res.Body, &evalResponse)
 	assert.Equal(t, evalResponse.Result, "5", "Expected result 5, but got %s", evalResponse.Result)
 	assert.Nil(t, err, "Expected valid result, but got", err)
 	assert.Equal(t, res.Code, http.StatusOK, "Expected Status 200, but got %d", res.Code)
@@ -112,40 +95,106 @@ func TestEvaluateHandler(t *testing.T) {
 	res = httptest.NewRecorder()
 	router.ServeHTTP(res, req)
 
-	var errorResp EvaluationResponse
-	err = json.NewDecoder(res.Body).Decode(&errorResp)
-	assert.Equal(t, errorResp.Result, InvalidJSONError, "Expected message: %s, but got message: %s", InvalidJSONError, errorResp.Result)
 	assert.Equal(t, res.Code, http.StatusBadRequest, "Expected Status 400, but got %d", res.Code)
+	var envelope struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&envelope)
+	assert.Equal(t, envelope.Status, "error", "Expected an error envelope, but got %s", envelope.Status)
+	assert.NotEqual(t, envelope.Error, "", "Expected a validation error for the missing required field")
+
+	req = httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(validJSON))
+	req.Header.Set("Accept-Encoding", "gzip")
+	res = httptest.NewRecorder()
+	mw.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Header().Get("Content-Encoding"), "gzip", "Expected a gzip-encoded response")
+
+	gz, err := gzip.NewReader(res.Body)
+	assert.Nil(t, err, "Expected a valid gzip stream, but got", err)
+	decompressed, err := io.ReadAll(gz)
+	assert.Nil(t, err, "Expected to decompress the gzip body, but got", err)
+
+	evalResponse = EvaluationResponse{}
+	err = decodeEnvelope(strings.NewReader(string(decompressed)), &evalResponse)
+	assert.Nil(t, err, "Expected valid result, but got", err)
+	assert.Equal(t, evalResponse.Result, "5", "Expected result 5, but got %s", evalResponse.Result)
+
+	req = httptest.NewRequest(http.MethodOptions, EvaluateEndpoint, nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", POST_REQ)
+	res = httptest.NewRecorder()
+	mw.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Code, http.StatusNoContent, "Expected Status 204 for a CORS preflight, but got %d", res.Code)
+	assert.Equal(t, res.Header().Get("Access-Control-Allow-Origin"), "https://example.com", "Expected the origin to be echoed back")
+	assert.NotEqual(t, res.Header().Get("Access-Control-Allow-Methods"), "", "Expected allowed methods to be set")
 }
 
 func TestValidateHandler(t *testing.T) {
 	validJSON := `{"expression": "What is 10 divided by 5 multiplied by 2?"}`
 	invalidJSON := `{"exp": "What is 10 divided by 5 multiplied by 2?"}`
-	router := Router(ValidateEndpoint, validateHandler, POST_REQ)
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(ValidateEndpoint, JSONHandlerFunc(server.validateHandler), POST_REQ)
+	mw := Middleware(router)
 
 	req := httptest.NewRequest(POST_REQ, ValidateEndpoint, strings.NewReader(validJSON))
 	res := httptest.NewRecorder()
 	router.ServeHTTP(res, req)
 
-	var problemExpr ProblemExpression
-	err := json.NewDecoder(res.Body).Decode(&problemExpr)
+	var validationResp ValidationResponse
+	err := decodeEnvelope(res.Body, &validationResp)
 	assert.Nil(t, err, "Expected valid result, but got", err)
+	assert.True(t, validationResp.Valid, "Expected a valid expression")
 	assert.Equal(t, res.Code, http.StatusOK, "Expected Status 200, but got %d", res.Code)
 
 	req = httptest.NewRequest(POST_REQ, ValidateEndpoint, strings.NewReader(invalidJSON))
 	res = httptest.NewRecorder()
 	router.ServeHTTP(res, req)
 
-	var errorResp ValidationResponse
-	_ = json.NewDecoder(res.Body).Decode(&errorResp)
-	assert.Equal(t, errorResp.Reason, InvalidJSONError, "Expected message: %s, but got message: %s", InvalidJSONError, errorResp.Reason)
 	assert.Equal(t, res.Code, http.StatusBadRequest, "Expected Status 400, but got %d", res.Code)
+	var envelope struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	_ = json.NewDecoder(res.Body).Decode(&envelope)
+	assert.Equal(t, envelope.Status, "error", "Expected an error envelope, but got %s", envelope.Status)
+	assert.NotEqual(t, envelope.Error, "", "Expected a validation error for the missing required field")
+
+	req = httptest.NewRequest(POST_REQ, ValidateEndpoint, strings.NewReader(validJSON))
+	req.Header.Set("Accept-Encoding", "gzip")
+	res = httptest.NewRecorder()
+	mw.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Header().Get("Content-Encoding"), "gzip", "Expected a gzip-encoded response")
+
+	gz, err := gzip.NewReader(res.Body)
+	assert.Nil(t, err, "Expected a valid gzip stream, but got", err)
+	decompressed, err := io.ReadAll(gz)
+	assert.Nil(t, err, "Expected to decompress the gzip body, but got", err)
+
+	validationResp = ValidationResponse{}
+	err = decodeEnvelope(strings.NewReader(string(decompressed)), &validationResp)
+	assert.Nil(t, err, "Expected valid result, but got", err)
+	assert.True(t, validationResp.Valid, "Expected a valid expression")
+
+	req = httptest.NewRequest(http.MethodOptions, ValidateEndpoint, nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", POST_REQ)
+	res = httptest.NewRecorder()
+	mw.ServeHTTP(res, req)
+
+	assert.Equal(t, res.Code, http.StatusNoContent, "Expected Status 204 for a CORS preflight, but got %d", res.Code)
+	assert.Equal(t, res.Header().Get("Access-Control-Allow-Origin"), "https://example.com", "Expected the origin to be echoed back")
+	assert.NotEqual(t, res.Header().Get("Access-Control-Allow-Methods"), "", "Expected allowed methods to be set")
 }
 
 func TestErrorsHandler(t *testing.T) {
 	validJSON := `{"expression": "What is 100 minus?"}`
 	const loops = 5
-	router := Router(EvaluateEndpoint, evaluateHandler, POST_REQ)
+	server := NewServer(NewMemoryErrorStore())
+	router := Router(EvaluateEndpoint, JSONHandlerFunc(server.evaluateHandler), POST_REQ)
 
 	for i := 0; i < loops; i++ {
 		postReq := httptest.NewRequest(POST_REQ, EvaluateEndpoint, strings.NewReader(validJSON))
@@ -162,10 +211,10 @@ func TestErrorsHandler(t *testing.T) {
 	getReq, _ := http.NewRequest(GET_REQ, ErrorsEndpoint, nil)
 	getRes := httptest.NewRecorder()
 
-	Router(ErrorsEndpoint, errorsHandler, GET_REQ).ServeHTTP(getRes, getReq)
+	Router(ErrorsEndpoint, JSONHandlerFunc(server.errorsHandler), GET_REQ).ServeHTTP(getRes, getReq)
 
 	var errorsInfo []ErrorInfo
-	json.NewDecoder(getRes.Body).Decode(&errorsInfo)
+	decodeEnvelope(getRes.Body, &errorsInfo)
 
 	for _, errors := range errorsInfo {
 		if errors.Endpoint == EvaluateEndpoint {